@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/orisano/pixelmatch"
+)
+
+// MatchFile is a convenience wrapper around pixelmatch.MatchPixel that
+// opens the two named image files and, if they are JPEGs carrying EXIF
+// metadata, normalizes their orientation before comparing them. This
+// avoids spurious 100% diffs when comparing photos taken in different
+// device orientations.
+func MatchFile(pathA, pathB string, opts ...pixelmatch.MatchOption) (int, error) {
+	imgA, err := openImage(pathA)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to open image(path=%v)", pathA)
+	}
+	imgB, err := openImage(pathB)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to open image(path=%v)", pathB)
+	}
+
+	exifA, err := extractExif(pathA)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read exif(path=%v)", pathA)
+	}
+	exifB, err := extractExif(pathB)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read exif(path=%v)", pathB)
+	}
+
+	opts = append(opts, pixelmatch.NormalizeOrientation(exifA, exifB))
+	return pixelmatch.MatchPixel(imgA, imgB, opts...)
+}
+
+// extractExif returns the payload of the JPEG APP1 EXIF segment in the
+// named file, or nil if the file is not a JPEG or carries no EXIF data.
+func extractExif(path string) (io.Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil, nil // not a JPEG
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			break
+		}
+		marker := data[i+1]
+		if marker == 0xd8 || marker == 0xd9 {
+			i += 2
+			continue
+		}
+		if marker == 0xda { // start of scan: no more markers follow
+			break
+		}
+		length := int(data[i+2])<<8 | int(data[i+3])
+		if length < 8 || i+2+length > len(data) {
+			break // malformed segment length
+		}
+		if marker == 0xe1 && string(data[i+4:i+4+6]) == "Exif\x00\x00" {
+			return bytes.NewReader(data[i+4 : i+2+length]), nil
+		}
+		i += 2 + length
+	}
+	return nil, nil
+}
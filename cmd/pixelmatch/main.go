@@ -68,6 +68,7 @@ func run() error {
 	flag.Var(&antiAliased, "aacolor", "anti aliased color")
 	diffColor := colorValue(color.RGBA{R: 255})
 	flag.Var(&diffColor, "diffcolor", "diff color")
+	exif := flag.Bool("exif", false, "normalize EXIF orientation (JPEG only) before comparing")
 
 	flag.Parse()
 
@@ -77,14 +78,6 @@ func run() error {
 		flag.PrintDefaults()
 		os.Exit(2)
 	}
-	img1, err := openImage(args[0])
-	if err != nil {
-		return errors.Wrapf(err, "failed to open image(path=%v)", args[0])
-	}
-	img2, err := openImage(args[1])
-	if err != nil {
-		return errors.Wrapf(err, "failed to open image(path=%v)", args[1])
-	}
 
 	var out image.Image
 	opts := []pixelmatch.MatchOption{
@@ -98,9 +91,22 @@ func run() error {
 		opts = append(opts, pixelmatch.IncludeAntiAlias)
 	}
 
-	_, err = pixelmatch.MatchPixel(img1, img2, opts...)
-	if err != nil {
-		return errors.Wrap(err, "failed to match pixel")
+	if *exif {
+		if _, err := MatchFile(args[0], args[1], opts...); err != nil {
+			return errors.Wrap(err, "failed to match pixel")
+		}
+	} else {
+		img1, err := openImage(args[0])
+		if err != nil {
+			return errors.Wrapf(err, "failed to open image(path=%v)", args[0])
+		}
+		img2, err := openImage(args[1])
+		if err != nil {
+			return errors.Wrapf(err, "failed to open image(path=%v)", args[1])
+		}
+		if _, err := pixelmatch.MatchPixel(img1, img2, opts...); err != nil {
+			return errors.Wrap(err, "failed to match pixel")
+		}
 	}
 
 	format := "png"
@@ -0,0 +1,40 @@
+package pixelmatch
+
+import "image"
+
+// paletteLUTOf precomputes an rgba lookup table for img's palette, one
+// entry per index, so readLine can convert *image.Paletted pixels with
+// a slice index instead of a color.Color interface call per pixel. It
+// returns nil for images that are not palette-indexed.
+func paletteLUTOf(img image.Image) []rgba {
+	p, ok := img.(*image.Paletted)
+	if !ok {
+		return nil
+	}
+	lut := make([]rgba, len(p.Palette))
+	for i, c := range p.Palette {
+		r, g, b, a := c.RGBA()
+		lut[i] = rgba{r, g, b, a}
+	}
+	return lut
+}
+
+// ycbcrToRGB converts a Y'CbCr triple to 8-bit RGB using the same
+// fixed-point math as color.YCbCrToRGB.
+func ycbcrToRGB(y, cb, cr uint8) (uint8, uint8, uint8) {
+	yy1 := int32(y) * 0x10101
+	cb1 := int32(cb) - 128
+	cr1 := int32(cr) - 128
+
+	r := yy1 + 91881*cr1
+	g := yy1 - 22554*cb1 - 46802*cr1
+	b := yy1 + 116130*cb1
+	return clamp8(r), clamp8(g), clamp8(b)
+}
+
+func clamp8(v int32) uint8 {
+	if uint32(v)&0xff000000 == 0 {
+		return uint8(v >> 16)
+	}
+	return uint8(^(v >> 31))
+}
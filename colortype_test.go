@@ -0,0 +1,179 @@
+package pixelmatch
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func Test_FastPaths(t *testing.T) {
+	t.Run("YCbCr identical images report no diff", func(t *testing.T) {
+		a := image.NewYCbCr(image.Rect(0, 0, 8, 8), image.YCbCrSubsampleRatio420)
+		b := image.NewYCbCr(image.Rect(0, 0, 8, 8), image.YCbCrSubsampleRatio420)
+		fillYCbCr(a, 200, 90, 160)
+		fillYCbCr(b, 200, 90, 160)
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("YCbCr matches an equivalent RGBA image", func(t *testing.T) {
+		a := image.NewYCbCr(image.Rect(0, 0, 8, 8), image.YCbCrSubsampleRatio444)
+		fillYCbCr(a, 200, 90, 160)
+		r, g, bl := ycbcrToRGB(200, 90, 160)
+		b := solidImage(image.Rect(0, 0, 8, 8), color.RGBA{R: r, G: g, B: bl, A: 0xff})
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("YCbCr detects a changed pixel", func(t *testing.T) {
+		a := image.NewYCbCr(image.Rect(0, 0, 8, 8), image.YCbCrSubsampleRatio444)
+		b := image.NewYCbCr(image.Rect(0, 0, 8, 8), image.YCbCrSubsampleRatio444)
+		fillYCbCr(a, 200, 90, 160)
+		fillYCbCr(b, 200, 90, 160)
+		b.Y[b.YOffset(4, 4)] = 16
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff == 0 {
+			t.Errorf("expected a nonzero diff for the changed pixel")
+		}
+	})
+
+	t.Run("CMYK identical images report no diff", func(t *testing.T) {
+		a := image.NewCMYK(image.Rect(0, 0, 8, 8))
+		b := image.NewCMYK(image.Rect(0, 0, 8, 8))
+		fillCMYK(a, 10, 20, 30, 40)
+		fillCMYK(b, 10, 20, 30, 40)
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("CMYK matches an equivalent RGBA image", func(t *testing.T) {
+		a := image.NewCMYK(image.Rect(0, 0, 8, 8))
+		fillCMYK(a, 10, 20, 30, 40)
+		want := color.CMYK{C: 10, M: 20, Y: 30, K: 40}
+		r, g, bl, _ := want.RGBA()
+		b := solidImage(image.Rect(0, 0, 8, 8), color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(bl >> 8), A: 0xff})
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("CMYK detects a changed pixel", func(t *testing.T) {
+		a := image.NewCMYK(image.Rect(0, 0, 8, 8))
+		b := image.NewCMYK(image.Rect(0, 0, 8, 8))
+		fillCMYK(a, 10, 20, 30, 40)
+		fillCMYK(b, 10, 20, 30, 40)
+		b.SetCMYK(4, 4, color.CMYK{C: 255, M: 255, Y: 255, K: 255})
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff == 0 {
+			t.Errorf("expected a nonzero diff for the changed pixel")
+		}
+	})
+
+	t.Run("Paletted identical images report no diff", func(t *testing.T) {
+		palette := color.Palette{color.White, color.Black}
+		a := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		b := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		fillPaletted(a, 0)
+		fillPaletted(b, 0)
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("Paletted matches an equivalent RGBA image", func(t *testing.T) {
+		palette := color.Palette{color.White, color.Black}
+		a := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		fillPaletted(a, 1)
+		b := solidImage(image.Rect(0, 0, 8, 8), color.Black)
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("Paletted detects a changed pixel even with identical palettes", func(t *testing.T) {
+		palette := color.Palette{color.White, color.Black}
+		a := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		b := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		fillPaletted(a, 0)
+		fillPaletted(b, 0)
+		b.SetColorIndex(4, 4, 1)
+
+		diff, err := MatchPixel(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff == 0 {
+			t.Errorf("expected a nonzero diff for the changed pixel")
+		}
+	})
+}
+
+func fillYCbCr(img *image.YCbCr, y, cb, cr uint8) {
+	r := img.Bounds()
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.Y[img.YOffset(px, py)] = y
+			img.Cb[img.COffset(px, py)] = cb
+			img.Cr[img.COffset(px, py)] = cr
+		}
+	}
+}
+
+func fillCMYK(img *image.CMYK, c, m, y, k uint8) {
+	r := img.Bounds()
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.SetCMYK(px, py, color.CMYK{C: c, M: m, Y: y, K: k})
+		}
+	}
+}
+
+func fillPaletted(img *image.Paletted, index uint8) {
+	r := img.Bounds()
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.SetColorIndex(px, py, index)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package pixelmatch
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// ErrMaskSizeNotMatch is returned by MatchPixel when an IgnoreMask's
+// bounds do not equal the bounds of the images being compared.
+var ErrMaskSizeNotMatch = errors.New("mask size does not match image size")
+
+// IgnoreMask tells MatchPixel to skip every pixel where mask's alpha
+// channel is 0: those pixels never contribute to the diff count, never
+// trigger the anti-alias heuristic, and are rendered in the WriteTo
+// output as MaskedColor (or the original grayscale, if MaskedColor is
+// not set). mask's bounds must equal the compared images' bounds, or
+// MatchPixel returns ErrMaskSizeNotMatch.
+func IgnoreMask(mask *image.Alpha) MatchOption {
+	return func(o *MatchOptions) {
+		o.ignoreMask = mask
+	}
+}
+
+// IgnoreRects is a convenience alternative to IgnoreMask for excluding
+// rectangular regions, such as timestamps or cursors, from comparison.
+func IgnoreRects(rects ...image.Rectangle) MatchOption {
+	return func(o *MatchOptions) {
+		o.ignoreRects = append(o.ignoreRects, rects...)
+	}
+}
+
+// MaskedColor sets the color rendered for ignored pixels in the WriteTo
+// output. Without it, ignored pixels are rendered the same as any other
+// matching pixel (grayscale blended with the background).
+func MaskedColor(c color.Color) MatchOption {
+	return func(o *MatchOptions) {
+		maskedColor := color.RGBAModel.Convert(c).(color.RGBA)
+		o.maskedColor = &maskedColor
+	}
+}
+
+func (o *MatchOptions) ignored(x, y int) bool {
+	if o.ignoreMask != nil && o.ignoreMask.AlphaAt(x, y).A == 0 {
+		return true
+	}
+	p := image.Pt(x, y)
+	for _, r := range o.ignoreRects {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeMaskedPixel renders an ignored pixel into the 4-byte outLine
+// slot for d: MaskedColor if set, otherwise the same grayscale
+// treatment given to any other matching pixel.
+func writeMaskedPixel(d []uint8, c *rgba, maskedColor *color.RGBA, aa float64) {
+	if maskedColor != nil {
+		d[0], d[1], d[2] = maskedColor.R, maskedColor.G, maskedColor.B
+		return
+	}
+	r, g, b, a := rgbaFromColor(c)
+	v := uint8(blend(rgbaToY(r, g, b), aa*a))
+	d[0], d[1], d[2] = v, v, v
+}
@@ -0,0 +1,74 @@
+package pixelmatch
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func Test_IgnoreMask(t *testing.T) {
+	a := solidImage(image.Rect(0, 0, 10, 10), color.White)
+	b := solidImage(image.Rect(0, 0, 10, 10), color.White)
+	b.Set(2, 2, color.Black)
+	b.Set(8, 8, color.Black)
+
+	mask := image.NewAlpha(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			mask.SetAlpha(x, y, color.Alpha{A: 0xff})
+		}
+	}
+	mask.SetAlpha(2, 2, color.Alpha{A: 0})
+
+	diff, err := MatchPixel(a, b, IncludeAntiAlias, IgnoreMask(mask))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != 1 {
+		t.Errorf("expected the masked pixel to be excluded, got diff=%d, want 1", diff)
+	}
+}
+
+func Test_IgnoreMask_SizeMismatch(t *testing.T) {
+	a := solidImage(image.Rect(0, 0, 10, 10), color.White)
+	b := solidImage(image.Rect(0, 0, 10, 10), color.White)
+	mask := image.NewAlpha(image.Rect(0, 0, 5, 5))
+
+	if _, err := MatchPixel(a, b, IgnoreMask(mask)); err != ErrMaskSizeNotMatch {
+		t.Errorf("expected ErrMaskSizeNotMatch, got %v", err)
+	}
+}
+
+func Test_IgnoreRects(t *testing.T) {
+	a := solidImage(image.Rect(0, 0, 10, 10), color.White)
+	b := solidImage(image.Rect(0, 0, 10, 10), color.White)
+	b.Set(1, 1, color.Black)
+	b.Set(9, 9, color.Black)
+
+	diff, err := MatchPixel(a, b, IncludeAntiAlias, IgnoreRects(image.Rect(0, 0, 5, 5)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != 1 {
+		t.Errorf("expected only the pixel outside the ignored rect to count, got diff=%d, want 1", diff)
+	}
+}
+
+func Test_MaskedColor(t *testing.T) {
+	a := solidImage(image.Rect(0, 0, 4, 4), color.White)
+	b := solidImage(image.Rect(0, 0, 4, 4), color.White)
+	b.Set(1, 1, color.Black)
+
+	var out image.Image
+	maskedColor := color.RGBA{R: 10, G: 20, B: 30, A: 0xff}
+	_, err := MatchPixel(a, b, IncludeAntiAlias, IgnoreRects(image.Rect(0, 0, 4, 4)), MaskedColor(maskedColor), WriteTo(&out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rgba := out.(*image.RGBA)
+	got := rgba.RGBAAt(1, 1)
+	if got.R != maskedColor.R || got.G != maskedColor.G || got.B != maskedColor.B {
+		t.Errorf("expected masked pixel to be rendered as %v, got %v", maskedColor, got)
+	}
+}
@@ -0,0 +1,131 @@
+package pixelmatch
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+)
+
+// ErrOrientationNotFound is returned by readOrientation when the given
+// EXIF data does not contain a recognizable Orientation tag.
+var ErrOrientationNotFound = errors.New("exif orientation tag not found")
+
+const orientationTag = 0x0112
+
+// NormalizeOrientation reads the EXIF Orientation tag (values 1-8) from
+// exifSrcA and exifSrcB and rotates/flips the corresponding image so
+// both are aligned to orientation 1 before MatchPixel compares them.
+// Either reader may be nil, in which case that image is left untouched.
+// A reader whose data has no Orientation tag is also left untouched;
+// any other read error is returned from MatchPixel.
+func NormalizeOrientation(exifSrcA, exifSrcB io.Reader) MatchOption {
+	return func(o *MatchOptions) {
+		o.orientationA = exifSrcA
+		o.orientationB = exifSrcB
+	}
+}
+
+func normalizeOrientation(img image.Image, exifSrc io.Reader) (image.Image, error) {
+	if exifSrc == nil {
+		return img, nil
+	}
+	orientation, err := readOrientation(exifSrc)
+	if errors.Is(err, ErrOrientationNotFound) {
+		return img, nil
+	} else if err != nil {
+		return img, err
+	}
+	return applyOrientation(img, orientation), nil
+}
+
+// readOrientation parses a minimal subset of the TIFF structure found
+// in a JPEG APP1 EXIF segment (with or without the leading "Exif\x00\x00"
+// marker) and returns the value of the Orientation tag.
+func readOrientation(r io.Reader) (int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) >= 6 && string(data[:6]) == "Exif\x00\x00" {
+		data = data[6:]
+	}
+	if len(data) < 8 {
+		return 0, ErrOrientationNotFound
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, ErrOrientationNotFound
+	}
+
+	ifdOffset := order.Uint32(data[4:8])
+	if int(ifdOffset)+2 > len(data) {
+		return 0, ErrOrientationNotFound
+	}
+	count := int(order.Uint16(data[ifdOffset : ifdOffset+2]))
+	entries := data[ifdOffset+2:]
+	for i := 0; i < count && (i+1)*12 <= len(entries); i++ {
+		entry := entries[i*12 : i*12+12]
+		if order.Uint16(entry[0:2]) != orientationTag {
+			continue
+		}
+		value := int(order.Uint16(entry[8:10]))
+		if value < 1 || value > 8 {
+			return 0, ErrOrientationNotFound
+		}
+		return value, nil
+	}
+	return 0, ErrOrientationNotFound
+}
+
+// applyOrientation rotates/flips img so that it is aligned to EXIF
+// orientation 1, swapping width and height for the 90/270-degree and
+// transpose/transverse cases (5-8).
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation == 1 {
+		return img
+	}
+
+	rect := img.Bounds()
+	w, h := rect.Dx(), rect.Dy()
+	var dst *image.RGBA
+	if orientation >= 5 {
+		dst = image.NewRGBA(image.Rect(0, 0, h, w))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := orientedPoint(orientation, x, y, w, h)
+			dst.Set(dx, dy, img.At(rect.Min.X+x, rect.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+func orientedPoint(orientation, x, y, w, h int) (int, int) {
+	switch orientation {
+	case 2: // mirror horizontal
+		return w - 1 - x, y
+	case 3: // rotate 180
+		return w - 1 - x, h - 1 - y
+	case 4: // mirror vertical
+		return x, h - 1 - y
+	case 5: // transpose
+		return y, x
+	case 6: // rotate 90 CW
+		return h - 1 - y, x
+	case 7: // transverse
+		return h - 1 - y, w - 1 - x
+	case 8: // rotate 270 CW
+		return y, w - 1 - x
+	default:
+		return x, y
+	}
+}
@@ -0,0 +1,259 @@
+package pixelmatch
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// Parallel splits the comparison across workers goroutines, each
+// handling a horizontal strip of the image. Strips overlap by 2 rows
+// on both ends so the 5-line neighborhood used by isAntiAliased and
+// hasManySiblings stays correct at strip boundaries; this overlap is
+// free because each imageLineReader reads directly from the shared
+// source image rather than a pre-sliced copy. workers <= 1 runs the
+// existing single-threaded path.
+func Parallel(workers int) MatchOption {
+	return func(o *MatchOptions) {
+		o.parallelWorkers = workers
+	}
+}
+
+func parallelMatchPixel(a, b image.Image, rect image.Rectangle, options *MatchOptions, collectMask bool) (int, []bool, error) {
+	workers := options.parallelWorkers
+	if h := rect.Dy(); workers > h {
+		workers = h
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	var out *image.RGBA
+	if options.writeTo != nil {
+		out = image.NewRGBA(rect)
+	}
+	var mask []bool
+	if collectMask {
+		mask = make([]bool, rect.Dx()*rect.Dy())
+	}
+
+	if isIdenticalParallel(a, b, workers) {
+		if out != nil && !options.diffMask {
+			parallelStrips(rect.Dy(), workers, func(y0, y1 int) {
+				writeIdenticalStrip(out, a, rect, options, rect.Min.Y+y0, rect.Min.Y+y1)
+			})
+		}
+		if options.writeTo != nil {
+			*options.writeTo = out
+		}
+		return 0, mask, nil
+	}
+
+	maxDelta := 35215 * options.threshold * options.threshold
+	diffs := make([]int, workers)
+	parallelStripsIndexed(rect.Dy(), workers, func(w, y0, y1 int) {
+		diffs[w] = matchStrip(a, b, rect, options, maxDelta, rect.Min.Y+y0, rect.Min.Y+y1, out, mask)
+	})
+
+	diff := 0
+	for _, d := range diffs {
+		diff += d
+	}
+
+	if options.writeTo != nil {
+		*options.writeTo = out
+	}
+	return diff, mask, nil
+}
+
+// parallelStrips runs fn once per worker over disjoint row ranges
+// [y0, y1) covering [0, h), waiting for all of them to finish.
+func parallelStrips(h, workers int, fn func(y0, y1 int)) {
+	parallelStripsIndexed(h, workers, func(_, y0, y1 int) { fn(y0, y1) })
+}
+
+func parallelStripsIndexed(h, workers int, fn func(w, y0, y1 int)) {
+	strip := (h + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		y0 := w * strip
+		y1 := minInt(y0+strip, h)
+		if y0 >= y1 {
+			continue
+		}
+		wg.Add(1)
+		go func(w, y0, y1 int) {
+			defer wg.Done()
+			fn(w, y0, y1)
+		}(w, y0, y1)
+	}
+	wg.Wait()
+}
+
+func writeIdenticalStrip(out *image.RGBA, a image.Image, rect image.Rectangle, options *MatchOptions, y0, y1 int) {
+	aa := options.alpha / 255
+	aLine := make([]rgba, rect.Dx())
+	aLUT := paletteLUTOf(a)
+	for y := y0; y < y1; y++ {
+		readLine(aLine, a, y, aLUT)
+		for i := range aLine {
+			x := rect.Min.X + i
+			if options.maskedColor != nil && options.ignored(x, y) {
+				c := *options.maskedColor
+				out.SetRGBA(x, y, color.RGBA{R: c.R, G: c.G, B: c.B, A: 255})
+				continue
+			}
+			r, g, b, av := rgbaFromColor(&aLine[i])
+			v := uint8(blend(rgbaToY(r, g, b), av*aa))
+			out.SetRGBA(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+}
+
+// matchStrip compares rows [y0, y1) of a and b, seeding its own pair of
+// imageLineReaders at y0; their 5-line window reads the 2 rows of
+// overlap above y0 directly from the shared full-image rect.
+func matchStrip(a, b image.Image, rect image.Rectangle, options *MatchOptions, maxDelta float64, y0, y1 int, out *image.RGBA, mask []bool) int {
+	aa := options.alpha / 255
+	diff := 0
+
+	var outLine []uint8
+	if out != nil {
+		outLine = make([]uint8, rect.Dx()*4)
+	}
+
+	ar := newImageLineReader(a, y0)
+	br := newImageLineReader(b, y0)
+	for y := y0; y < y1 && ar.Next() && br.Next(); y++ {
+		aLine := ar.Line()
+		bLine := br.Line()
+
+		if out != nil {
+			for i := range outLine {
+				outLine[i] = 0xff
+			}
+		}
+
+		for i := range aLine {
+			x := rect.Min.X + i
+			if options.ignored(x, y) {
+				if out != nil && !options.diffMask {
+					d := outLine[i*4 : i*4+4 : i*4+4]
+					writeMaskedPixel(d, &aLine[i], options.maskedColor, aa)
+				}
+				continue
+			}
+			delta := colorDelta(&aLine[i], &bLine[i], false)
+			if math.Abs(delta) > maxDelta {
+				if !options.includeAA && (isAntiAliased(ar, br, x, y) || isAntiAliased(br, ar, x, y)) {
+					if out != nil && !options.diffMask {
+						c := options.antiAliasedColor
+						d := outLine[i*4 : i*4+4 : i*4+4]
+						d[0], d[1], d[2] = c.R, c.G, c.B
+					}
+				} else {
+					if out != nil {
+						c := options.diffColor
+						if delta < 0 && options.diffColorAlt != nil {
+							c = *options.diffColorAlt
+						}
+						d := outLine[i*4 : i*4+4 : i*4+4]
+						d[0], d[1], d[2] = c.R, c.G, c.B
+					}
+					if mask != nil {
+						mask[(y-rect.Min.Y)*rect.Dx()+i] = true
+					}
+					diff++
+				}
+			} else {
+				if out != nil && !options.diffMask {
+					r, g, b, av := rgbaFromColor(&aLine[i])
+					v := uint8(blend(rgbaToY(r, g, b), aa*av))
+					d := outLine[i*4 : i*4+4 : i*4+4]
+					d[0], d[1], d[2] = v, v, v
+				}
+			}
+		}
+		if out != nil {
+			copy(out.Pix[out.PixOffset(rect.Min.X, y):], outLine)
+		}
+	}
+	return diff
+}
+
+type pixBuffer struct {
+	pix    []uint8
+	stride int
+	rect   image.Rectangle
+}
+
+func pixBufferOf(img image.Image) (pixBuffer, bool) {
+	switch v := img.(type) {
+	case *image.RGBA:
+		return pixBuffer{v.Pix, v.Stride, v.Rect}, true
+	case *image.RGBA64:
+		return pixBuffer{v.Pix, v.Stride, v.Rect}, true
+	case *image.NRGBA:
+		return pixBuffer{v.Pix, v.Stride, v.Rect}, true
+	case *image.NRGBA64:
+		return pixBuffer{v.Pix, v.Stride, v.Rect}, true
+	case *image.Gray:
+		return pixBuffer{v.Pix, v.Stride, v.Rect}, true
+	case *image.Gray16:
+		return pixBuffer{v.Pix, v.Stride, v.Rect}, true
+	case *image.CMYK:
+		return pixBuffer{v.Pix, v.Stride, v.Rect}, true
+	default:
+		return pixBuffer{}, false
+	}
+}
+
+// isIdenticalParallel is the Parallel counterpart of isIdentical: for
+// the pixel types that expose a raw Pix/Stride buffer, it compares
+// row strips with bytes.Equal across workers goroutines instead of a
+// single full-buffer comparison. Other image types (e.g. *image.YCbCr,
+// *image.Paletted, or anything reached only through the At(x, y)
+// interface) fall back to the sequential isIdentical.
+func isIdenticalParallel(a, b image.Image, workers int) bool {
+	pa, ok := pixBufferOf(a)
+	if !ok {
+		return isIdentical(a, b)
+	}
+	pb, ok := pixBufferOf(b)
+	if !ok || reflect.TypeOf(a) != reflect.TypeOf(b) || pa.rect.Dx() != pb.rect.Dx() || pa.rect.Dy() != pb.rect.Dy() {
+		return isIdentical(a, b)
+	}
+
+	h := pa.rect.Dy()
+	if workers > h {
+		workers = h
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]bool, workers)
+	for i := range results {
+		results[i] = true // workers with an empty row range (more workers than rows) default to equal
+	}
+	parallelStripsIndexed(h, workers, func(w, y0, y1 int) {
+		ok := true
+		for y := y0; y < y1; y++ {
+			if !bytes.Equal(pa.pix[y*pa.stride:y*pa.stride+pa.stride], pb.pix[y*pb.stride:y*pb.stride+pb.stride]) {
+				ok = false
+				break
+			}
+		}
+		results[w] = ok
+	})
+
+	for _, ok := range results {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
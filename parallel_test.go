@@ -0,0 +1,104 @@
+package pixelmatch
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func checkerboard(w, h, diffEveryN int) (*image.RGBA, *image.RGBA) {
+	a := image.NewRGBA(image.Rect(0, 0, w, h))
+	b := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 255}
+			a.SetRGBA(x, y, c)
+			if diffEveryN > 0 && (x+y*w)%diffEveryN == 0 {
+				c.R ^= 0xff
+			}
+			b.SetRGBA(x, y, c)
+		}
+	}
+	return a, b
+}
+
+func Test_Parallel(t *testing.T) {
+	const w, h = 37, 29 // deliberately awkward dims: no worker count below divides evenly
+	imgA, imgB := checkerboard(w, h, 7)
+
+	for _, workers := range []int{2, 3, 5, 8} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			wantDiff, err := MatchPixel(imgA, imgB, IncludeAntiAlias)
+			if err != nil {
+				t.Fatalf("unexpected error (sequential): %v", err)
+			}
+			gotDiff, err := MatchPixel(imgA, imgB, IncludeAntiAlias, Parallel(workers))
+			if err != nil {
+				t.Fatalf("unexpected error (parallel): %v", err)
+			}
+			if gotDiff != wantDiff {
+				t.Errorf("diff count mismatch: sequential=%d parallel(%d)=%d", wantDiff, workers, gotDiff)
+			}
+
+			var wantOut, gotOut image.Image
+			if _, err := MatchPixel(imgA, imgB, IncludeAntiAlias, WriteTo(&wantOut)); err != nil {
+				t.Fatalf("unexpected error (sequential, WriteTo): %v", err)
+			}
+			if _, err := MatchPixel(imgA, imgB, IncludeAntiAlias, Parallel(workers), WriteTo(&gotOut)); err != nil {
+				t.Fatalf("unexpected error (parallel, WriteTo): %v", err)
+			}
+			assertImagesEqual(t, wantOut.(*image.RGBA), gotOut.(*image.RGBA))
+
+			var wantDiffMaskOut, gotDiffMaskOut image.Image
+			if _, err := MatchPixel(imgA, imgB, IncludeAntiAlias, EnableDiffMask, WriteTo(&wantDiffMaskOut)); err != nil {
+				t.Fatalf("unexpected error (sequential, EnableDiffMask): %v", err)
+			}
+			if _, err := MatchPixel(imgA, imgB, IncludeAntiAlias, Parallel(workers), EnableDiffMask, WriteTo(&gotDiffMaskOut)); err != nil {
+				t.Fatalf("unexpected error (parallel, EnableDiffMask): %v", err)
+			}
+			assertImagesEqual(t, wantDiffMaskOut.(*image.RGBA), gotDiffMaskOut.(*image.RGBA))
+		})
+	}
+}
+
+func assertImagesEqual(t *testing.T, want, got *image.RGBA) {
+	t.Helper()
+	if want.Bounds() != got.Bounds() {
+		t.Fatalf("bounds mismatch: want %v, got %v", want.Bounds(), got.Bounds())
+	}
+	r := want.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if wc, gc := want.RGBAAt(x, y), got.RGBAAt(x, y); wc != gc {
+				t.Fatalf("pixel (%d,%d) mismatch: want %v, got %v", x, y, wc, gc)
+			}
+		}
+	}
+}
+
+func BenchmarkMatchPixel4K(b *testing.B) {
+	const w, h = 3840, 2160
+	imgA, imgB := checkerboard(w, h, 997)
+
+	b.Run("sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := MatchPixel(imgA, imgB); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	for _, workers := range []int{2, 4, 8} {
+		workers := workers
+		b.Run(fmt.Sprintf("parallel-%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := MatchPixel(imgA, imgB, Parallel(workers)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"image"
 	"image/color"
+	"io"
 	"math"
+
+	"golang.org/x/image/draw"
 )
 
 var ErrImageSizesNotMatch = errors.New("image sizes do not match")
@@ -19,6 +22,15 @@ type MatchOptions struct {
 	diffColorAlt     *color.RGBA
 	diffMask         bool
 	writeTo          *image.Image
+	resizeScaler     draw.Scaler
+	orientationA     io.Reader
+	orientationB     io.Reader
+	minRegionPixels  int
+	maxRegions       int
+	parallelWorkers  int
+	ignoreMask       *image.Alpha
+	ignoreRects      []image.Rectangle
+	maskedColor      *color.RGBA
 }
 
 type MatchOption func(*MatchOptions)
@@ -82,6 +94,16 @@ func rgbaFromColor(c *rgba) (r, g, b, a float64) {
 }
 
 func MatchPixel(a, b image.Image, opts ...MatchOption) (int, error) {
+	diff, _, _, err := matchPixel(a, b, opts, false)
+	return diff, err
+}
+
+// matchPixel is the shared implementation behind MatchPixel and
+// MatchPixelDetailed. When collectMask is true, it also returns a
+// row-major boolean mask (sized to the returned rect) marking every
+// pixel counted as a diff, for callers that need to derive diff
+// regions.
+func matchPixel(a, b image.Image, opts []MatchOption, collectMask bool) (int, []bool, image.Rectangle, error) {
 	options := MatchOptions{
 		threshold:        0.1,
 		alpha:            0.1,
@@ -92,42 +114,68 @@ func MatchPixel(a, b image.Image, opts ...MatchOption) (int, error) {
 		opt(&options)
 	}
 
+	var err error
+	if a, err = normalizeOrientation(a, options.orientationA); err != nil {
+		return 0, nil, image.Rectangle{}, err
+	}
+	if b, err = normalizeOrientation(b, options.orientationB); err != nil {
+		return 0, nil, image.Rectangle{}, err
+	}
+
 	if !a.Bounds().Eq(b.Bounds()) {
-		return 0, ErrImageSizesNotMatch
+		if options.resizeScaler == nil {
+			return 0, nil, image.Rectangle{}, ErrImageSizesNotMatch
+		}
+		a, b = resizeToMatch(a, b, options.resizeScaler)
+	}
+	rect := a.Bounds()
+
+	if options.ignoreMask != nil && !options.ignoreMask.Bounds().Eq(rect) {
+		return 0, nil, image.Rectangle{}, ErrMaskSizeNotMatch
+	}
+
+	if options.parallelWorkers > 1 {
+		diff, mask, err := parallelMatchPixel(a, b, rect, &options, collectMask)
+		return diff, mask, rect, err
 	}
 
 	var out *image.RGBA
 	if options.writeTo != nil {
-		out = image.NewRGBA(a.Bounds())
+		out = image.NewRGBA(rect)
+	}
+	var mask []bool
+	if collectMask {
+		mask = make([]bool, rect.Dx()*rect.Dy())
 	}
 	aa := options.alpha / 255
 	if isIdentical(a, b) { // fast path if identical
 		if out != nil && !options.diffMask {
-			rect := a.Bounds()
 			aLine := make([]rgba, rect.Dx())
+			aLUT := paletteLUTOf(a)
 			for y := rect.Min.Y; y < rect.Max.Y; y++ {
-				readLine(aLine, a, y)
+				readLine(aLine, a, y, aLUT)
 				for i := range aLine {
 					x := rect.Min.X + i
+					if options.maskedColor != nil && options.ignored(x, y) {
+						c := *options.maskedColor
+						out.SetRGBA(x, y, color.RGBA{R: c.R, G: c.G, B: c.B, A: 255})
+						continue
+					}
 					r, g, b, a := rgbaFromColor(&aLine[i])
 					v := uint8(blend(rgbaToY(r, g, b), a*aa))
 					out.SetRGBA(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
 				}
 			}
 		}
-		return 0, nil
+		return 0, mask, rect, nil
 	}
 
 	maxDelta := 35215 * options.threshold * options.threshold
 	diff := 0
 
-	rect := a.Bounds()
 	var outLine []uint8
 	if out != nil {
 		outLine = make([]uint8, rect.Dx()*4)
-		for i := range outLine {
-			outLine[i] = 0xff
-		}
 	}
 
 	y := rect.Min.Y
@@ -137,9 +185,22 @@ func MatchPixel(a, b image.Image, opts ...MatchOption) (int, error) {
 		aLine := ar.Line()
 		bLine := br.Line()
 
+		if out != nil {
+			for i := range outLine {
+				outLine[i] = 0xff
+			}
+		}
+
 		for i := range aLine {
-			delta := colorDelta(&aLine[i], &bLine[i], false)
 			x := rect.Min.X + i
+			if options.ignored(x, y) {
+				if out != nil && !options.diffMask {
+					d := outLine[i*4 : i*4+4 : i*4+4]
+					writeMaskedPixel(d, &aLine[i], options.maskedColor, aa)
+				}
+				continue
+			}
+			delta := colorDelta(&aLine[i], &bLine[i], false)
 			if math.Abs(delta) > maxDelta {
 				if !options.includeAA && (isAntiAliased(ar, br, x, y) || isAntiAliased(br, ar, x, y)) {
 					if out != nil && !options.diffMask {
@@ -165,6 +226,9 @@ func MatchPixel(a, b image.Image, opts ...MatchOption) (int, error) {
 							d[2] = c.B
 						}
 					}
+					if mask != nil {
+						mask[(y-rect.Min.Y)*rect.Dx()+i] = true
+					}
 					diff++
 				}
 			} else {
@@ -187,7 +251,7 @@ func MatchPixel(a, b image.Image, opts ...MatchOption) (int, error) {
 		*options.writeTo = out
 	}
 
-	return diff, nil
+	return diff, mask, rect, nil
 }
 
 func colorDelta(a, b *rgba, yOnly bool) float64 {
@@ -364,10 +428,40 @@ func isIdentical(a, b image.Image) bool {
 		if ok && equals(x.Pix, y.Pix, x.Stride, y.Stride, x.Rect) {
 			return true
 		}
+	case *image.YCbCr:
+		y, ok := b.(*image.YCbCr)
+		if ok && x.SubsampleRatio == y.SubsampleRatio && x.Rect.Eq(y.Rect) &&
+			bytes.Equal(x.Y, y.Y) && bytes.Equal(x.Cb, y.Cb) && bytes.Equal(x.Cr, y.Cr) {
+			return true
+		}
+	case *image.CMYK:
+		y, ok := b.(*image.CMYK)
+		if ok && equals(x.Pix, y.Pix, x.Stride, y.Stride, x.Rect) {
+			return true
+		}
+	case *image.Paletted:
+		y, ok := b.(*image.Paletted)
+		if ok && equals(x.Pix, y.Pix, x.Stride, y.Stride, x.Rect) && palettesEqual(x.Palette, y.Palette) {
+			return true
+		}
 	}
 	return false
 }
 
+func palettesEqual(a, b color.Palette) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		ar, ag, ab, aa := a[i].RGBA()
+		br, bg, bb, ba := b[i].RGBA()
+		if ar != br || ag != bg || ab != bb || aa != ba {
+			return false
+		}
+	}
+	return true
+}
+
 func equals(pixA, pixB []uint8, strideA, strideB int, rect image.Rectangle) bool {
 	w := rect.Dx()
 	h := rect.Dy()
@@ -382,7 +476,7 @@ func equals(pixA, pixB []uint8, strideA, strideB int, rect image.Rectangle) bool
 	return true
 }
 
-func readLine(dst []rgba, img image.Image, y int) {
+func readLine(dst []rgba, img image.Image, y int, plut []rgba) {
 	rect := img.Bounds()
 	switch v := img.(type) {
 	case *image.RGBA:
@@ -448,6 +542,31 @@ func readLine(dst []rgba, img image.Image, y int) {
 			y := uint32(s[0])<<8 | uint32(s[1])
 			dst[i] = rgba{y, y, y, 0xffff}
 		}
+	case *image.YCbCr:
+		for i := range dst {
+			x := rect.Min.X + i
+			yi := v.YOffset(x, y)
+			ci := v.COffset(x, y)
+			r, g, b := ycbcrToRGB(v.Y[yi], v.Cb[ci], v.Cr[ci])
+			rr, gg, bb := uint32(r), uint32(g), uint32(b)
+			dst[i] = rgba{rr<<8 | rr, gg<<8 | gg, bb<<8 | bb, 0xffff}
+		}
+	case *image.CMYK:
+		lineOffset := v.PixOffset(rect.Min.X, y)
+		for i := range dst {
+			offset := lineOffset + i*4
+			s := v.Pix[offset : offset+4 : offset+4]
+			w := 255 - uint32(s[3])
+			r := uint32(255-s[0]) * w / 255
+			g := uint32(255-s[1]) * w / 255
+			b := uint32(255-s[2]) * w / 255
+			dst[i] = rgba{r<<8 | r, g<<8 | g, b<<8 | b, 0xffff}
+		}
+	case *image.Paletted:
+		lineOffset := v.PixOffset(rect.Min.X, y)
+		for i := range dst {
+			dst[i] = plut[v.Pix[lineOffset+i]]
+		}
 	default:
 		for i := range dst {
 			r, g, b, a := v.At(rect.Min.X+i, y).RGBA()
@@ -458,6 +577,7 @@ func readLine(dst []rgba, img image.Image, y int) {
 
 type imageLineReader struct {
 	image image.Image
+	plut  []rgba
 
 	rect  image.Rectangle
 	width int
@@ -471,6 +591,7 @@ func newImageLineReader(img image.Image, y int) *imageLineReader {
 	width := rect.Dx()
 	return &imageLineReader{
 		image: img,
+		plut:  paletteLUTOf(img),
 		rect:  rect,
 		width: width,
 		y:     y,
@@ -486,7 +607,7 @@ func (r *imageLineReader) Next() bool {
 			y := r.y + i - 2
 			if r.rect.Min.Y <= y && y < r.rect.Max.Y {
 				line := make([]rgba, r.width)
-				readLine(line, r.image, y)
+				readLine(line, r.image, y, r.plut)
 				r.lines[i] = line
 			}
 		}
@@ -501,7 +622,7 @@ func (r *imageLineReader) Next() bool {
 			if r.lines[4] == nil {
 				r.lines[4] = make([]rgba, r.width)
 			}
-			readLine(r.lines[4], r.image, r.y+2)
+			readLine(r.lines[4], r.image, r.y+2, r.plut)
 		} else {
 			r.lines[4] = nil
 		}
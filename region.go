@@ -0,0 +1,62 @@
+package pixelmatch
+
+import "image"
+
+// Region is a connected component of contiguous differing pixels.
+type Region struct {
+	Bounds     image.Rectangle
+	PixelCount int
+}
+
+// MatchResult is the return value of MatchPixelDetailed.
+type MatchResult struct {
+	Diff      int
+	DiffRatio float64
+	Bounds    image.Rectangle
+	Regions   []Region
+}
+
+// MinRegionPixels drops connected-component regions smaller than n
+// pixels from the MatchResult returned by MatchPixelDetailed. It has
+// no effect on MatchPixel's diff count.
+func MinRegionPixels(n int) MatchOption {
+	return func(o *MatchOptions) {
+		o.minRegionPixels = n
+	}
+}
+
+// MaxRegions caps the number of regions reported by MatchPixelDetailed
+// to the n largest (by pixel count). A value of 0, the default, means
+// unlimited.
+func MaxRegions(n int) MatchOption {
+	return func(o *MatchOptions) {
+		o.maxRegions = n
+	}
+}
+
+// MatchPixelDetailed behaves like MatchPixel but additionally reports
+// the bounding box of all differing pixels and the set of connected
+// components ("regions") of contiguous diffs, so callers can describe
+// where images differ instead of just by how much.
+func MatchPixelDetailed(a, b image.Image, opts ...MatchOption) (MatchResult, error) {
+	diff, mask, rect, err := matchPixel(a, b, opts, true)
+	if err != nil {
+		return MatchResult{}, err
+	}
+
+	result := MatchResult{
+		Diff:      diff,
+		DiffRatio: float64(diff) / float64(rect.Dx()*rect.Dy()),
+	}
+	if diff == 0 {
+		return result, nil
+	}
+
+	var o MatchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	result.Bounds, result.Regions = labelRegions(mask, rect, o.minRegionPixels, o.maxRegions)
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package pixelmatch
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func Test_MatchPixelDetailed(t *testing.T) {
+	t.Run("bounds cover every diff regardless of MinRegionPixels", func(t *testing.T) {
+		a := solidImage(image.Rect(0, 0, 10, 10), color.White)
+		b := solidImage(image.Rect(0, 0, 10, 10), color.White)
+
+		// A 5x5 block of diffs near the origin...
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 5; x++ {
+				b.Set(x, y, color.Black)
+			}
+		}
+		// ...and one isolated noise pixel far away.
+		b.Set(9, 9, color.Black)
+
+		result, err := MatchPixelDetailed(a, b, IncludeAntiAlias, MinRegionPixels(10))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Diff != 26 {
+			t.Fatalf("expected 26 diffs, got %d", result.Diff)
+		}
+		wantBounds := image.Rect(0, 0, 10, 10)
+		if result.Bounds != wantBounds {
+			t.Errorf("Bounds should cover all diffs including the filtered noise pixel, got %v, want %v", result.Bounds, wantBounds)
+		}
+		// The isolated 1px region is dropped by MinRegionPixels(10), so
+		// only the 5x5 block should be reported.
+		if len(result.Regions) != 1 {
+			t.Fatalf("expected 1 surviving region, got %d", len(result.Regions))
+		}
+		if result.Regions[0].PixelCount != 25 {
+			t.Errorf("expected surviving region to have 25 pixels, got %d", result.Regions[0].PixelCount)
+		}
+	})
+
+	t.Run("two separated regions are reported independently", func(t *testing.T) {
+		a := solidImage(image.Rect(0, 0, 10, 10), color.White)
+		b := solidImage(image.Rect(0, 0, 10, 10), color.White)
+
+		b.Set(0, 0, color.Black)
+		b.Set(9, 9, color.Black)
+
+		result, err := MatchPixelDetailed(a, b, IncludeAntiAlias)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Diff != 2 {
+			t.Fatalf("expected 2 diffs, got %d", result.Diff)
+		}
+		if len(result.Regions) != 2 {
+			t.Fatalf("expected 2 regions, got %d", len(result.Regions))
+		}
+	})
+
+	t.Run("MaxRegions caps to the largest regions", func(t *testing.T) {
+		a := solidImage(image.Rect(0, 0, 10, 10), color.White)
+		b := solidImage(image.Rect(0, 0, 10, 10), color.White)
+
+		b.Set(0, 0, color.Black)
+		for y := 5; y < 9; y++ {
+			for x := 5; x < 9; x++ {
+				b.Set(x, y, color.Black)
+			}
+		}
+
+		result, err := MatchPixelDetailed(a, b, IncludeAntiAlias, MaxRegions(1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Regions) != 1 {
+			t.Fatalf("expected 1 region after MaxRegions(1), got %d", len(result.Regions))
+		}
+		if result.Regions[0].PixelCount != 16 {
+			t.Errorf("expected the larger 16px region to survive, got %d", result.Regions[0].PixelCount)
+		}
+	})
+}
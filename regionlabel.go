@@ -0,0 +1,149 @@
+package pixelmatch
+
+import (
+	"image"
+	"sort"
+)
+
+// labelRegions runs a single-pass two-scan union-find connected-component
+// labeling (8-connectivity) over mask, a row-major w*h boolean diff mask
+// for rect, and returns the bounding box of all set pixels together with
+// one Region per component. Components smaller than minPixels are
+// dropped; if maxRegions is positive, only the maxRegions largest
+// (by PixelCount) are kept.
+func labelRegions(mask []bool, rect image.Rectangle, minPixels, maxRegions int) (image.Rectangle, []Region) {
+	w, h := rect.Dx(), rect.Dy()
+
+	labels := make([]int32, w*h)
+	parent := []int32{0} // 1-based; parent[0] is unused
+
+	newLabel := func() int32 {
+		parent = append(parent, int32(len(parent)))
+		return int32(len(parent) - 1)
+	}
+	var find func(l int32) int32
+	find = func(l int32) int32 {
+		for parent[l] != l {
+			parent[l] = parent[parent[l]]
+			l = parent[l]
+		}
+		return l
+	}
+	union := func(a, b int32) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if ra < rb {
+			parent[rb] = ra
+		} else {
+			parent[ra] = rb
+		}
+	}
+
+	set := func(x, y int) bool {
+		if x < 0 || x >= w || y < 0 || y >= h {
+			return false
+		}
+		return mask[y*w+x]
+	}
+	labelAt := func(x, y int) int32 {
+		if x < 0 || x >= w || y < 0 || y >= h {
+			return 0
+		}
+		return labels[y*w+x]
+	}
+
+	// Scan 1: assign provisional labels from already-visited neighbors
+	// (W, NW, N, NE) and union equivalent labels.
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if !set(x, y) {
+				continue
+			}
+			var min int32
+			var found bool
+			for _, n := range [4][2]int{{x - 1, y}, {x - 1, y - 1}, {x, y - 1}, {x + 1, y - 1}} {
+				if !set(n[0], n[1]) {
+					continue
+				}
+				l := labelAt(n[0], n[1])
+				if !found || l < min {
+					min = l
+				}
+				found = true
+			}
+			if !found {
+				labels[y*w+x] = newLabel()
+				continue
+			}
+			labels[y*w+x] = min
+			for _, n := range [4][2]int{{x - 1, y}, {x - 1, y - 1}, {x, y - 1}, {x + 1, y - 1}} {
+				if set(n[0], n[1]) {
+					union(min, labelAt(n[0], n[1]))
+				}
+			}
+		}
+	}
+
+	// Scan 2: resolve each label to its component's representative and
+	// accumulate bounds/pixel counts.
+	components := map[int32]*Region{}
+	var order []int32
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			l := labels[y*w+x]
+			if l == 0 {
+				continue
+			}
+			root := find(l)
+			px, py := rect.Min.X+x, rect.Min.Y+y
+			r, ok := components[root]
+			if !ok {
+				r = &Region{Bounds: image.Rect(px, py, px+1, py+1)}
+				components[root] = r
+				order = append(order, root)
+			}
+			if px < r.Bounds.Min.X {
+				r.Bounds.Min.X = px
+			}
+			if py < r.Bounds.Min.Y {
+				r.Bounds.Min.Y = py
+			}
+			if px+1 > r.Bounds.Max.X {
+				r.Bounds.Max.X = px + 1
+			}
+			if py+1 > r.Bounds.Max.Y {
+				r.Bounds.Max.Y = py + 1
+			}
+			r.PixelCount++
+		}
+	}
+
+	// bounds covers every differing pixel regardless of minPixels, so it
+	// always matches MatchResult.Diff; only the reported regions are
+	// filtered by size.
+	var bounds image.Rectangle
+	regions := make([]Region, 0, len(order))
+	for _, root := range order {
+		r := *components[root]
+		if bounds.Empty() {
+			bounds = r.Bounds
+		} else {
+			bounds = bounds.Union(r.Bounds)
+		}
+		if r.PixelCount < minPixels {
+			continue
+		}
+		regions = append(regions, r)
+	}
+
+	if maxRegions > 0 && len(regions) > maxRegions {
+		sort.Slice(regions, func(i, j int) bool {
+			return regions[i].PixelCount > regions[j].PixelCount
+		})
+		regions = regions[:maxRegions]
+	}
+
+	return bounds, regions
+}
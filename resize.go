@@ -0,0 +1,57 @@
+package pixelmatch
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Preset scalers for Resize, trading quality for speed.
+var (
+	NearestNeighbor draw.Scaler = draw.NearestNeighbor
+	ApproxBiLinear  draw.Scaler = draw.ApproxBiLinear
+	BiLinear        draw.Scaler = draw.BiLinear
+	CatmullRom      draw.Scaler = draw.CatmullRom
+)
+
+// Resize makes MatchPixel tolerate mismatched image bounds instead of
+// returning ErrImageSizesNotMatch: both images are scaled to a shared
+// target rectangle (the larger of the two) using scaler before the
+// pixel comparison runs. The diff image produced via WriteTo, if any,
+// is therefore at the resized dimensions.
+func Resize(scaler draw.Scaler) MatchOption {
+	return func(o *MatchOptions) {
+		o.resizeScaler = scaler
+	}
+}
+
+func resizeToMatch(a, b image.Image, scaler draw.Scaler) (image.Image, image.Image) {
+	target := a.Bounds()
+	if area(b.Bounds()) > area(target) {
+		target = b.Bounds()
+	}
+	target = image.Rect(0, 0, target.Dx(), target.Dy())
+	return resizeTo(a, target, scaler), resizeTo(b, target, scaler)
+}
+
+// resizeTo returns img as an image whose Bounds() exactly equal target
+// (always a (0,0)-origin rectangle). img is returned as-is only when its
+// bounds are already target; an image of the right size but a non-zero
+// origin (e.g. a sub-image) is still copied so every resized image ends
+// up on the same (0,0) origin that matchPixel's shared rect assumes.
+func resizeTo(img image.Image, target image.Rectangle, scaler draw.Scaler) image.Image {
+	if img.Bounds().Eq(target) {
+		return img
+	}
+	dst := image.NewRGBA(target)
+	if img.Bounds().Dx() == target.Dx() && img.Bounds().Dy() == target.Dy() {
+		draw.Draw(dst, target, img, img.Bounds().Min, draw.Src)
+		return dst
+	}
+	scaler.Scale(dst, target, img, img.Bounds(), draw.Src, nil)
+	return dst
+}
+
+func area(r image.Rectangle) int {
+	return r.Dx() * r.Dy()
+}
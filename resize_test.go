@@ -0,0 +1,59 @@
+package pixelmatch
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidImage(r image.Rectangle, c color.Color) *image.RGBA {
+	img := image.NewRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func Test_Resize(t *testing.T) {
+	t.Run("upscales the smaller image and matches", func(t *testing.T) {
+		small := solidImage(image.Rect(0, 0, 5, 5), color.White)
+		big := solidImage(image.Rect(0, 0, 10, 10), color.White)
+
+		diff, err := MatchPixel(small, big, Resize(NearestNeighbor))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("non-zero-origin sub-image at the target size does not panic", func(t *testing.T) {
+		// A 10x10 sub-image whose origin is not (0,0), paired with a
+		// 5x5 image that needs upscaling to 10x10. resizeTo must not
+		// return the sub-image as-is (it would keep matchPixel
+		// indexing with an offset rect against a (0,0)-origin buffer).
+		base := solidImage(image.Rect(0, 0, 20, 20), color.White)
+		sub := base.SubImage(image.Rect(5, 5, 15, 15)).(*image.RGBA)
+		small := solidImage(image.Rect(0, 0, 5, 5), color.White)
+
+		diff, err := MatchPixel(small, sub, Resize(NearestNeighbor))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff != 0 {
+			t.Errorf("expected 0 diff, got %d", diff)
+		}
+	})
+
+	t.Run("without Resize, mismatched bounds still error", func(t *testing.T) {
+		small := solidImage(image.Rect(0, 0, 5, 5), color.White)
+		big := solidImage(image.Rect(0, 0, 10, 10), color.White)
+
+		if _, err := MatchPixel(small, big); err != ErrImageSizesNotMatch {
+			t.Errorf("expected ErrImageSizesNotMatch, got %v", err)
+		}
+	})
+}